@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ModuleConfig describes a single module instance declared in config.yaml: which Module type to
+// instantiate, and the attribute sub-tree handed to its Initialize.
+type ModuleConfig struct {
+	Type       string               `yaml:"type"`
+	Attributes moduleAttributeStore `yaml:"attributes"`
+}
+
+// BootstrapConfig is the root of config.yaml, listing every module instance bootstrap should
+// schedule. Multiple entries may share the same Type, e.g. one covid-incidence module per district.
+type BootstrapConfig struct {
+	Modules []ModuleConfig `yaml:"modules"`
+}
+
+// loadBootstrapConfig reads and parses the module list from path.
+func loadBootstrapConfig(path string) (*BootstrapConfig, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read config file: %w", err)
+	}
+
+	cfg := &BootstrapConfig{}
+	if err := yaml.Unmarshal(bytes, cfg); err != nil {
+		return nil, fmt.Errorf("could not parse config file: %w", err)
+	}
+
+	return cfg, nil
+}
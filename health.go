@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Prometheus counters tracking the bot's interaction with the upstream API and Discord.
+var (
+	metricFetchesTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "schoolnt_fetches_total",
+		Help: "Total number of upstream data fetches attempted.",
+	})
+	metricFetchErrorsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "schoolnt_fetch_errors_total",
+		Help: "Total number of upstream data fetches that failed.",
+	})
+	metricAlertsSentTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "schoolnt_alerts_sent_total",
+		Help: "Total number of Distanzunterricht alerts posted.",
+	})
+	metricRateLimitWaitsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "schoolnt_rate_limit_waits_total",
+		Help: "Total number of times a request blocked on a rate-limit bucket.",
+	})
+)
+
+// health tracks the state surfaced by the /healthz endpoint.
+var health = struct {
+	mu             sync.Mutex
+	lastFetchOK    time.Time
+	discordSession *discordgoSessionState
+}{}
+
+// discordgoSessionState is the subset of discordgo.Session state /healthz reports, kept as its own
+// type so health doesn't need to import discordgo.
+type discordgoSessionState struct {
+	Connected bool
+}
+
+// markFetchSucceeded records that an upstream fetch just completed successfully.
+func markFetchSucceeded() {
+	health.mu.Lock()
+	defer health.mu.Unlock()
+	health.lastFetchOK = time.Now()
+}
+
+// setDiscordConnected records the Discord session's connection state, as reported by its
+// Ready/Disconnect handlers.
+func setDiscordConnected(connected bool) {
+	health.mu.Lock()
+	defer health.mu.Unlock()
+	health.discordSession = &discordgoSessionState{Connected: connected}
+}
+
+// healthzResponse is the JSON body served by /healthz.
+type healthzResponse struct {
+	LastSuccessfulFetch time.Time `json:"lastSuccessfulFetch"`
+	DiscordConnected    bool      `json:"discordConnected"`
+}
+
+// startHealthServer starts an HTTP server exposing /healthz and /metrics on addr. It stops once
+// ctx is cancelled.
+func startHealthServer(ctx context.Context, addr string) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		health.mu.Lock()
+		resp := healthzResponse{LastSuccessfulFetch: health.lastFetchOK}
+		if health.discordSession != nil {
+			resp.DiscordConnected = health.discordSession.Connected
+		}
+		health.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+
+	srv := &http.Server{Addr: addr, Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			appLog.Error().Err(err).Msg("health server stopped unexpectedly")
+		}
+	}()
+
+	return srv
+}
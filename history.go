@@ -0,0 +1,139 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// historyDBPath is where the SQLite database recording every fetched snapshot lives.
+const historyDBPath = "history.sqlite3"
+
+// sparkBlocks are the unicode block characters used to render a sparkline, from lowest to highest.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// historyDB is the shared handle to historyDBPath, opened once in initHistoryDB.
+var historyDB *sql.DB
+
+// snapshot is a single recorded Response.Data[district] entry, timestamped at fetch time.
+type snapshot struct {
+	Timestamp time.Time
+	District  string
+	Incidence float64
+	Cases     uint32
+	Deaths    uint32
+}
+
+// initHistoryDB opens historyDBPath and ensures the snapshots table exists.
+func initHistoryDB() error {
+	db, err := sql.Open("sqlite3", historyDBPath)
+	if err != nil {
+		return fmt.Errorf("could not open history database: %w", err)
+	}
+
+	const schema = `
+		CREATE TABLE IF NOT EXISTS snapshots (
+			timestamp INTEGER NOT NULL,
+			district  TEXT    NOT NULL,
+			incidence REAL    NOT NULL,
+			cases     INTEGER NOT NULL,
+			deaths    INTEGER NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_snapshots_district_timestamp ON snapshots (district, timestamp);
+	`
+	if _, err := db.Exec(schema); err != nil {
+		return fmt.Errorf("could not create snapshots table: %w", err)
+	}
+
+	historyDB = db
+	return nil
+}
+
+// recordSnapshot stores data's entry for district at timestamp.
+func recordSnapshot(district string, timestamp time.Time, data *Response) error {
+	d := data.Data[district]
+
+	_, err := historyDB.Exec(
+		`INSERT INTO snapshots (timestamp, district, incidence, cases, deaths) VALUES (?, ?, ?, ?, ?)`,
+		timestamp.Unix(), district, d.WeekIncidence, d.Cases, d.Deaths,
+	)
+	if err != nil {
+		return fmt.Errorf("could not record snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// incidenceSeries returns district's recorded incidence, oldest first, going back since.
+func incidenceSeries(district string, since time.Time) ([]snapshot, error) {
+	rows, err := historyDB.Query(
+		`SELECT timestamp, incidence, cases, deaths FROM snapshots WHERE district = ? AND timestamp >= ? ORDER BY timestamp ASC`,
+		district, since.Unix(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not query snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	var series []snapshot
+	for rows.Next() {
+		var unix int64
+		s := snapshot{District: district}
+		if err := rows.Scan(&unix, &s.Incidence, &s.Cases, &s.Deaths); err != nil {
+			return nil, fmt.Errorf("could not scan snapshot: %w", err)
+		}
+		s.Timestamp = time.Unix(unix, 0)
+		series = append(series, s)
+	}
+
+	return series, rows.Err()
+}
+
+// incidenceDelta returns the change in incidence for district between the oldest and the newest
+// snapshot recorded within the last n+1 days, or an error if that window holds no snapshot. When
+// the recorded history is shorter than n days, the delta covers only the history that exists.
+func incidenceDelta(district string, n int) (float64, error) {
+	series, err := incidenceSeries(district, time.Now().AddDate(0, 0, -n-1))
+	if err != nil {
+		return 0, err
+	}
+
+	if len(series) == 0 {
+		return 0, fmt.Errorf("no history for district %s", district)
+	}
+
+	return series[len(series)-1].Incidence - series[0].Incidence, nil
+}
+
+// sparkline renders series' incidence values as a line of unicode block characters.
+func sparkline(series []snapshot) string {
+	if len(series) == 0 {
+		return ""
+	}
+
+	min, max := series[0].Incidence, series[0].Incidence
+	for _, s := range series {
+		if s.Incidence < min {
+			min = s.Incidence
+		}
+		if s.Incidence > max {
+			max = s.Incidence
+		}
+	}
+
+	span := max - min
+	out := make([]rune, len(series))
+	for i, s := range series {
+		if span == 0 {
+			out[i] = sparkBlocks[0]
+			continue
+		}
+
+		idx := int((s.Incidence - min) / span * float64(len(sparkBlocks)-1))
+		out[i] = sparkBlocks[idx]
+	}
+
+	return string(out)
+}
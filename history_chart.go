@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+// renderIncidenceChart plots district's recorded incidence over the last n days and returns the
+// resulting PNG.
+func renderIncidenceChart(district string, n int) ([]byte, error) {
+	series, err := incidenceSeries(district, time.Now().AddDate(0, 0, -n))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(series) == 0 {
+		return nil, fmt.Errorf("no history for district %s", district)
+	}
+
+	pts := make(plotter.XYs, len(series))
+	for i, s := range series {
+		pts[i].X = float64(s.Timestamp.Unix())
+		pts[i].Y = s.Incidence
+	}
+
+	p := plot.New()
+	p.Title.Text = fmt.Sprintf("Week incidence — district %s", district)
+	p.X.Label.Text = "Date"
+	p.Y.Label.Text = "Incidence"
+	p.X.Tick.Marker = plot.TimeTicks{Format: "02.01"}
+
+	line, err := plotter.NewLine(pts)
+	if err != nil {
+		return nil, fmt.Errorf("could not build chart line: %w", err)
+	}
+	p.Add(line, plotter.NewGrid())
+
+	writer, err := p.WriterTo(6*vg.Inch, 3*vg.Inch, "png")
+	if err != nil {
+		return nil, fmt.Errorf("could not render chart: %w", err)
+	}
+
+	buf := &bytes.Buffer{}
+	if _, err := writer.WriteTo(buf); err != nil {
+		return nil, fmt.Errorf("could not encode chart: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name   string
+		series []snapshot
+		want   string
+	}{
+		{
+			name:   "empty series",
+			series: nil,
+			want:   "",
+		},
+		{
+			name:   "flat series uses the lowest block",
+			series: []snapshot{{Incidence: 50}, {Incidence: 50}, {Incidence: 50}},
+			want:   "▁▁▁",
+		},
+		{
+			name:   "ascending series spans the full block range",
+			series: []snapshot{{Incidence: 0}, {Incidence: 50}, {Incidence: 100}},
+			want:   "▁▄█",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sparkline(tt.series); got != tt.want {
+				t.Errorf("sparkline(%v) = %q, want %q", tt.series, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIncidenceDelta(t *testing.T) {
+	withTestHistoryDB(t)
+
+	district := "09676"
+	now := time.Now()
+
+	seed := []struct {
+		daysAgo   int
+		incidence float64
+	}{
+		{daysAgo: 20, incidence: 40},
+		{daysAgo: 14, incidence: 60},
+		{daysAgo: 7, incidence: 90},
+		{daysAgo: 0, incidence: 120},
+	}
+	for _, s := range seed {
+		insertSnapshot(t, district, now.AddDate(0, 0, -s.daysAgo), s.incidence)
+	}
+
+	tests := []struct {
+		name     string
+		district string
+		n        int
+		want     float64
+		wantErr  bool
+	}{
+		// window covers the last n+1 days: only the 7- and 0-day-old snapshots fall inside it.
+		{name: "7 day delta", district: district, n: 7, want: 120 - 90},
+		// window covers the last n+1 days: the 14-, 7- and 0-day-old snapshots fall inside it.
+		{name: "14 day delta", district: district, n: 14, want: 120 - 60},
+		// window covers every seeded snapshot.
+		{name: "30 day delta spans the whole series", district: district, n: 30, want: 120 - 40},
+		{name: "no history for an unseeded district", district: "00000", n: 7, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := incidenceDelta(tt.district, tt.n)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("incidenceDelta(%d) = %v, want error", tt.n, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("incidenceDelta(%d): %v", tt.n, err)
+			}
+			if got != tt.want {
+				t.Errorf("incidenceDelta(%d) = %v, want %v", tt.n, got, tt.want)
+			}
+		})
+	}
+}
+
+// withTestHistoryDB points the package-level historyDB at a fresh in-memory database for the
+// duration of t, restoring the previous handle once t completes.
+func withTestHistoryDB(t *testing.T) {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("could not open in-memory history database: %v", err)
+	}
+
+	const schema = `
+		CREATE TABLE snapshots (
+			timestamp INTEGER NOT NULL,
+			district  TEXT    NOT NULL,
+			incidence REAL    NOT NULL,
+			cases     INTEGER NOT NULL,
+			deaths    INTEGER NOT NULL
+		);
+	`
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("could not create snapshots table: %v", err)
+	}
+
+	prev := historyDB
+	historyDB = db
+	t.Cleanup(func() {
+		_ = db.Close()
+		historyDB = prev
+	})
+}
+
+// insertSnapshot records a single incidence reading for district at timestamp directly, sidestepping
+// recordSnapshot's full Response shape since these tests only care about the stored incidence.
+func insertSnapshot(t *testing.T, district string, timestamp time.Time, incidence float64) {
+	t.Helper()
+
+	_, err := historyDB.Exec(
+		`INSERT INTO snapshots (timestamp, district, incidence, cases, deaths) VALUES (?, ?, ?, 0, 0)`,
+		timestamp.Unix(), district, incidence,
+	)
+	if err != nil {
+		t.Fatalf("could not insert snapshot: %v", err)
+	}
+}
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// applicationCommands lists every slash command the bot registers on startup.
+var applicationCommands = []*discordgo.ApplicationCommand{
+	{
+		Name:        "incidence",
+		Description: "Fetch the current week-incidence for a district",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "district",
+				Description: "District key, e.g. 09676 for Miltenberg",
+				Required:    true,
+			},
+		},
+	},
+	{
+		Name:        "subscribe",
+		Description: "Post incidence alerts for a district in this channel",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "district",
+				Description: "District key, e.g. 09676 for Miltenberg",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "threshold",
+				Description: "Incidence threshold to ping everyone at",
+				Required:    true,
+			},
+		},
+	},
+	{
+		Name:        "history",
+		Description: "Chart a district's recorded incidence history",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "district",
+				Description: "District key, e.g. 09676 for Miltenberg",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "days",
+				Description: "How many days of history to chart (default 14)",
+			},
+		},
+	},
+	{
+		Name:                     "config",
+		Description:              "Manage the bot's subscriptions for this guild",
+		DefaultMemberPermissions: &adminPermission,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "list",
+				Description: "List the subscriptions active in this guild",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "remove",
+				Description: "Remove a subscription by district",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "district",
+						Description: "District key to remove",
+						Required:    true,
+					},
+				},
+			},
+		},
+	},
+}
+
+// adminPermission restricts an application command to guild administrators.
+var adminPermission int64 = discordgo.PermissionAdministrator
+
+// applicationCommandHandlers maps a slash command name to its handler.
+var applicationCommandHandlers = map[string]func(s *discordgo.Session, i *discordgo.InteractionCreate){
+	"incidence": handleIncidenceCommand,
+	"subscribe": handleSubscribeCommand,
+	"history":   handleHistoryCommand,
+	"config":    handleConfigCommand,
+}
+
+// interactionsDeps bundles what the interaction handlers need beyond the Discord session itself.
+var interactionsDeps ModuleDeps
+
+// registerApplicationCommands bulk-overwrites the bot's global application commands and wires up
+// the InteractionCreate router. It should be called once, after the Discord session is opened.
+func registerApplicationCommands(discord *discordgo.Session, deps ModuleDeps) error {
+	interactionsDeps = deps
+
+	discord.AddHandler(func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		if i.Type != discordgo.InteractionApplicationCommand {
+			return
+		}
+
+		handler, ok := applicationCommandHandlers[i.ApplicationCommandData().Name]
+		if !ok {
+			return
+		}
+
+		handler(s, i)
+	})
+
+	_, err := discord.ApplicationCommandBulkOverwrite(discord.State.User.ID, "", applicationCommands)
+	if err != nil {
+		return fmt.Errorf("could not register application commands: %w", err)
+	}
+
+	return nil
+}
+
+// replyEphemeral replies to an interaction with a message only the invoking user can see.
+func replyEphemeral(s *discordgo.Session, i *discordgo.InteractionCreate, content string) {
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// deferResponse acknowledges the interaction within Discord's 3-second window, before the slow
+// upstream fetch/chart render that follows. The ephemeral flag must be set here, on the initial
+// ack, since it can no longer be changed once the deferred response is edited. Callers must follow
+// up with editResponse.
+func deferResponse(s *discordgo.Session, i *discordgo.InteractionCreate, ephemeral bool) error {
+	resp := &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredChannelMessageWithSource}
+	if ephemeral {
+		resp.Data = &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral}
+	}
+
+	return s.InteractionRespond(i.Interaction, resp)
+}
+
+// editResponse fills in the deferred response from deferResponse with the final message content.
+func editResponse(s *discordgo.Session, i *discordgo.InteractionCreate, edit *discordgo.WebhookEdit) {
+	if _, err := s.InteractionResponseEdit(i.Interaction, edit); err != nil {
+		appLog.Error().Err(err).Str("command", i.ApplicationCommandData().Name).Msg("could not edit deferred interaction response")
+	}
+}
+
+// handleIncidenceCommand implements /incidence.
+func handleIncidenceCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	district := i.ApplicationCommandData().Options[0].StringValue()
+
+	if err := deferResponse(s, i, true); err != nil {
+		appLog.Error().Err(err).Msg("could not defer /incidence response")
+		return
+	}
+
+	data, err := fetchData(context.Background(), interactionsDeps.Client, district)
+	if err != nil {
+		content := fmt.Sprintf("could not fetch incidence for %s: %s", district, err)
+		editResponse(s, i, &discordgo.WebhookEdit{Content: &content})
+		return
+	}
+
+	d, ok := data.Data[district]
+	if !ok {
+		content := fmt.Sprintf("unknown district %s", district)
+		editResponse(s, i, &discordgo.WebhookEdit{Content: &content})
+		return
+	}
+
+	incidence := uint32(d.WeekIncidence)
+	content := fmt.Sprintf("Inzidenzwert für den %s: **%d**", getCurrentTimestamp(), incidence)
+	editResponse(s, i, &discordgo.WebhookEdit{Content: &content})
+}
+
+// handleHistoryCommand implements /history, charting a district's recorded incidence as a file
+// attachment.
+func handleHistoryCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	opts := i.ApplicationCommandData().Options
+	district := opts[0].StringValue()
+
+	days := 14
+	if len(opts) > 1 {
+		days = int(opts[1].IntValue())
+	}
+
+	if err := deferResponse(s, i, false); err != nil {
+		appLog.Error().Err(err).Msg("could not defer /history response")
+		return
+	}
+
+	png, err := renderIncidenceChart(district, days)
+	if err != nil {
+		content := fmt.Sprintf("could not chart history for %s: %s", district, err)
+		editResponse(s, i, &discordgo.WebhookEdit{Content: &content})
+		return
+	}
+
+	content := fmt.Sprintf("Incidence history for district %s, last %d days:", district, days)
+	editResponse(s, i, &discordgo.WebhookEdit{
+		Content: &content,
+		Files: []*discordgo.File{
+			{
+				Name:        fmt.Sprintf("%s-history.png", district),
+				ContentType: "image/png",
+				Reader:      bytes.NewReader(png),
+			},
+		},
+	})
+}
+
+// handleSubscribeCommand implements /subscribe, persisting a per-channel district subscription.
+func handleSubscribeCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	opts := i.ApplicationCommandData().Options
+	district := opts[0].StringValue()
+	threshold := opts[1].IntValue()
+
+	if !isConfiguredDistrict(district) {
+		replyEphemeral(s, i, fmt.Sprintf("no covid-incidence module is configured for district %s, ask an admin to add one to config.yaml", district))
+		return
+	}
+
+	if err := addSubscription(i.GuildID, i.ChannelID, district, threshold); err != nil {
+		replyEphemeral(s, i, fmt.Sprintf("could not save subscription: %s", err))
+		return
+	}
+
+	replyEphemeral(s, i, fmt.Sprintf("Subscribed this channel to district %s at threshold %d.", district, threshold))
+}
+
+// handleConfigCommand implements /config, a guild-admin-only view into this guild's subscriptions.
+func handleConfigCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	sub := i.ApplicationCommandData().Options[0]
+
+	switch sub.Name {
+	case "list":
+		subs := listSubscriptions(i.GuildID)
+		if len(subs) == 0 {
+			replyEphemeral(s, i, "No subscriptions in this guild.")
+			return
+		}
+
+		msg := "Subscriptions in this guild:\n"
+		for _, sc := range subs {
+			msg += fmt.Sprintf("- <#%s>: district %s, threshold %d\n", sc.ChannelID, sc.District, sc.Threshold)
+		}
+		replyEphemeral(s, i, msg)
+	case "remove":
+		district := sub.Options[0].StringValue()
+		if err := removeSubscription(i.ChannelID, district); err != nil {
+			replyEphemeral(s, i, fmt.Sprintf("could not remove subscription: %s", err))
+			return
+		}
+		replyEphemeral(s, i, fmt.Sprintf("Removed the subscription for district %s.", district))
+	}
+}
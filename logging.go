@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rs/zerolog"
+)
+
+// newLogger builds the zerolog.Logger used across the application, writing to logFile (or stderr
+// if empty) at the given level.
+func newLogger(level, logFile string) (zerolog.Logger, error) {
+	lvl, err := zerolog.ParseLevel(level)
+	if err != nil {
+		return zerolog.Logger{}, fmt.Errorf("invalid log level %q: %w", level, err)
+	}
+
+	out := os.Stderr
+	if logFile != "" {
+		f, err := os.OpenFile(logFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return zerolog.Logger{}, fmt.Errorf("could not open log file: %w", err)
+		}
+		out = f
+	}
+
+	return zerolog.New(out).Level(lvl).With().Timestamp().Logger(), nil
+}
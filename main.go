@@ -1,13 +1,13 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"flag"
 	"fmt"
 	"github.com/bwmarrin/discordgo"
 	"github.com/robfig/cron/v3"
-	"io"
+	"github.com/rs/zerolog"
 	"net/http"
 	"os"
 	"os/signal"
@@ -19,9 +19,6 @@ import (
 // See https://api.corona-zahlen.org/docs/endpoints/districts.html#districts-2.
 const endpoint = "https://api.corona-zahlen.org/districts/%s"
 
-// key specifies the district key of Miltenberg, used for the endpoint.
-const key = "09676"
-
 // timeFmt is the format used to display the date of a data fetch.
 const timeFmt = "02.01.2006"
 
@@ -56,66 +53,113 @@ type Response struct {
 
 // Config holds some user-defined values.
 type Config struct {
-	Timer     string
-	Token     string
-	ChannelID string
+	Token      string
+	ConfigPath string
+	LogLevel   string
+	LogFile    string
+	HealthAddr string
 }
 
 // config is an instance of Config used across the application.
 var config Config
 
+// appLog is the structured logger used across the application.
+var appLog zerolog.Logger
+
 func init() {
-	timer := flag.String("timer", "0 18 * * *", "Specifies the cron notation")
 	token := flag.String("token", "", "Specifies the Discord bot token")
-	channelID := flag.String("channel", "", "Specifies the Discord channel to use")
+	configPath := flag.String("config", "config.yaml", "Specifies the path to the module bootstrap config file")
+	logLevel := flag.String("log-level", "info", "Specifies the minimum log level (debug, info, warn, error)")
+	logFile := flag.String("log-file", "", "Specifies a file to log to, instead of stderr")
+	healthAddr := flag.String("health-addr", ":8080", "Specifies the address to serve /healthz and /metrics on")
 
 	config = Config{
-		Timer:     *timer,
-		Token:     *token,
-		ChannelID: *channelID,
+		Token:      *token,
+		ConfigPath: *configPath,
+		LogLevel:   *logLevel,
+		LogFile:    *logFile,
+		HealthAddr: *healthAddr,
 	}
 }
 
 func main() {
-	client := &http.Client{}
+	logger, err := newLogger(config.LogLevel, config.LogFile)
+	if err != nil {
+		panic(err)
+	}
+	appLog = logger
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	bootstrap, err := loadBootstrapConfig(config.ConfigPath)
+	if err != nil {
+		appLog.Fatal().Err(err).Msg("could not load bootstrap config")
+	}
+
+	client := newRESTClient(&http.Client{})
 	ctab := cron.New(cron.WithParser(cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)))
 
 	discord, err := newDiscordSession(config.Token)
 	if err != nil {
-		panic(err)
+		appLog.Fatal().Err(err).Msg("could not open Discord session")
+	}
+	discord.AddHandler(func(s *discordgo.Session, r *discordgo.Ready) { setDiscordConnected(true) })
+	discord.AddHandler(func(s *discordgo.Session, d *discordgo.Disconnect) { setDiscordConnected(false) })
+
+	deps := ModuleDeps{Discord: discord, Client: client}
+
+	if err := loadSubscriptions(); err != nil {
+		appLog.Fatal().Err(err).Msg("could not load subscriptions")
+	}
+
+	if err := initHistoryDB(); err != nil {
+		appLog.Fatal().Err(err).Msg("could not open history database")
 	}
 
-	if _, err := ctab.AddFunc(config.Timer, func() {
-		data, err := fetchData(client)
+	if err := registerApplicationCommands(discord, deps); err != nil {
+		appLog.Fatal().Err(err).Msg("could not register application commands")
+	}
+
+	for _, mc := range bootstrap.Modules {
+		mod, err := newModule(mc.Type)
 		if err != nil {
-			_, _ = fmt.Fprintf(os.Stderr, "could not fetch COVID data: %s\n", err)
-			return
+			appLog.Fatal().Err(err).Msg("could not create module")
 		}
 
-		timestamp := getCurrentTimestamp()
-		incidence := uint32(data.Data[key].WeekIncidence)
+		if err := mod.Initialize(mc.Attributes); err != nil {
+			appLog.Fatal().Err(err).Str("module", mc.Type).Msg("could not initialize module")
+		}
 
-		// Too lazy to handle errors but not too lazy to write this comment which probably took more
-		// time than adding a proper error check.
-		_, _ = discord.ChannelMessageSend(config.ChannelID, fmt.Sprintf("Inzidenzwert für den %s: **%d**", timestamp, incidence))
-		if incidence >= 165 {
-			mention, _ := everyone(discord)
-			_, _ = discord.ChannelMessageSend(config.ChannelID, fmt.Sprintf("%s Distanzunterricht, wooow", mention))
+		if err := mod.Setup(deps); err != nil {
+			appLog.Fatal().Err(err).Str("module", mc.Type).Msg("could not set up module")
+		}
+
+		mod, mc := mod, mc
+		if _, err := ctab.AddFunc(mod.Schedule(), func() {
+			if err := mod.Execute(ctx); err != nil {
+				appLog.Error().Err(err).Str("module", mc.Type).Msg("module execution failed")
+			}
+		}); err != nil {
+			appLog.Fatal().Err(err).Str("module", mc.Type).Msg("could not schedule module")
 		}
-	}); err != nil {
-		panic(err)
 	}
 
+	startHealthServer(ctx, config.HealthAddr)
+
 	ctab.Start()
+	appLog.Info().Msg("bot started")
+
+	<-ctx.Done()
+	appLog.Info().Msg("shutting down")
 
-	c := make(chan os.Signal, 2)
-	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
-	<-c
+	<-ctab.Stop().Done()
+	_ = discord.Close()
 }
 
-// everyone returns the mention for everyone.
-func everyone(discord *discordgo.Session) (string, error) {
-	c, err := discord.Channel(config.ChannelID)
+// everyone returns the mention for everyone in the guild owning channelID.
+func everyone(discord *discordgo.Session, channelID string) (string, error) {
+	c, err := discord.Channel(channelID)
 	if err != nil {
 		return "", err
 	}
@@ -148,20 +192,13 @@ func newDiscordSession(token string) (*discordgo.Session, error) {
 	return discord, discord.Open()
 }
 
-// fetchData sends a GET request to endpoint for the district identified by key and parses it.
-func fetchData(client *http.Client) (*Response, error) {
-	resp, err := client.Get(fmt.Sprintf(endpoint, key))
-	if err != nil {
-		return nil, err
-	}
-
-	defer resp.Body.Close()
-
-	bytes, err := io.ReadAll(resp.Body)
-	if err != nil {
+// fetchData sends a GET request to endpoint for the district identified by key and parses it,
+// going through client so the call is rate-limited and retried against districtsRoute.
+func fetchData(ctx context.Context, client *restClient, key string) (*Response, error) {
+	r := &Response{}
+	if err := client.getJSON(ctx, districtsRoute, fmt.Sprintf(endpoint, key), r); err != nil {
 		return nil, err
 	}
 
-	r := &Response{}
-	return r, json.Unmarshal(bytes, r)
+	return r, nil
 }
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// errValueNotSet is returned by moduleAttributeStore accessors when the requested key is absent.
+var errValueNotSet = errors.New("value not set")
+
+// errValueMismatch is returned by moduleAttributeStore accessors when the stored value has a
+// different type than the one requested.
+var errValueMismatch = errors.New("value type mismatch")
+
+// moduleAttributeStore holds a single module's attribute sub-tree, as declared under `attributes`
+// in config.yaml, so a module can read its own configuration without touching Config or flag.
+type moduleAttributeStore map[string]interface{}
+
+// String returns the string stored under key.
+func (s moduleAttributeStore) String(key string) (string, error) {
+	v, ok := s[key]
+	if !ok {
+		return "", fmt.Errorf("%s: %w", key, errValueNotSet)
+	}
+
+	str, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("%s: %w", key, errValueMismatch)
+	}
+
+	return str, nil
+}
+
+// MustString returns the string stored under key, panicking if it is absent or of a different type.
+func (s moduleAttributeStore) MustString(key string) string {
+	str, err := s.String(key)
+	if err != nil {
+		panic(err)
+	}
+
+	return str
+}
+
+// Int64 returns the integer stored under key.
+func (s moduleAttributeStore) Int64(key string) (int64, error) {
+	v, ok := s[key]
+	if !ok {
+		return 0, fmt.Errorf("%s: %w", key, errValueNotSet)
+	}
+
+	switch n := v.(type) {
+	case int64:
+		return n, nil
+	case int:
+		return int64(n), nil
+	case float64: // yaml/json unmarshal untyped numbers as float64
+		return int64(n), nil
+	default:
+		return 0, fmt.Errorf("%s: %w", key, errValueMismatch)
+	}
+}
+
+// MustInt64 returns the integer stored under key, panicking if it is absent or of a different type.
+func (s moduleAttributeStore) MustInt64(key string) int64 {
+	n, err := s.Int64(key)
+	if err != nil {
+		panic(err)
+	}
+
+	return n
+}
+
+// ModuleDeps bundles the dependencies shared across all modules, handed out in Module.Setup.
+type ModuleDeps struct {
+	Discord *discordgo.Session
+	Client  *restClient
+}
+
+// Module is implemented by every pluggable bot feature (COVID incidence reporter, stream notifier,
+// moderation command, ...). Bootstrap discovers instances by the `type` declared in config.yaml,
+// feeds each its own attribute sub-tree, and schedules Execute on its own cron entry.
+type Module interface {
+	// Initialize configures the module from its attribute sub-tree, before Setup is called.
+	Initialize(attrs moduleAttributeStore) error
+
+	// Setup wires up dependencies shared across all modules, such as the Discord session.
+	Setup(deps ModuleDeps) error
+
+	// Execute runs a single scheduled tick of the module. It must return promptly once ctx is
+	// cancelled, so a shutdown isn't held up by an in-flight fetch.
+	Execute(ctx context.Context) error
+
+	// Schedule returns the cron expression this module instance should run on.
+	Schedule() string
+}
+
+// moduleFactories holds the registered constructor for every known module type, keyed by the
+// `type` value used in config.yaml. Modules register themselves from an init func, mirroring the
+// way database/sql drivers register themselves.
+var moduleFactories = map[string]func() Module{}
+
+// RegisterModule makes the module type returned by factory available under name for use in
+// config.yaml. It is meant to be called from the init func of the file implementing the module.
+func RegisterModule(name string, factory func() Module) {
+	if _, exists := moduleFactories[name]; exists {
+		panic(fmt.Sprintf("module %q already registered", name))
+	}
+
+	moduleFactories[name] = factory
+}
+
+// newModule instantiates a fresh Module for the given config.yaml `type` name.
+func newModule(name string) (Module, error) {
+	factory, ok := moduleFactories[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown module type %q", name)
+	}
+
+	return factory(), nil
+}
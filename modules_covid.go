@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// covidModuleName is the config.yaml `type` this module registers itself under.
+const covidModuleName = "covid-incidence"
+
+func init() {
+	RegisterModule(covidModuleName, func() Module { return &covidIncidenceModule{} })
+}
+
+// configuredDistricts tracks every district with a bootstrapped covid-incidence module, so
+// /subscribe can reject a district nobody is polling instead of silently accepting a subscription
+// that will never fire.
+var configuredDistricts = struct {
+	mu   sync.Mutex
+	seen map[string]bool
+}{seen: map[string]bool{}}
+
+// isConfiguredDistrict reports whether district has a bootstrapped covid-incidence module.
+func isConfiguredDistrict(district string) bool {
+	configuredDistricts.mu.Lock()
+	defer configuredDistricts.mu.Unlock()
+	return configuredDistricts.seen[district]
+}
+
+// covidIncidenceModule posts the weekly COVID incidence for a district on its own schedule, and
+// pings everyone once it crosses the configured Distanzunterricht threshold.
+type covidIncidenceModule struct {
+	district  string
+	threshold int64
+	channelID string
+	schedule  string
+
+	discord    *discordgo.Session
+	client     *restClient
+	lastUpdate string
+}
+
+// Initialize reads this module instance's district, threshold, channel and cron schedule from attrs.
+func (m *covidIncidenceModule) Initialize(attrs moduleAttributeStore) error {
+	district, err := attrs.String("district")
+	if err != nil {
+		return err
+	}
+	m.district = district
+
+	channelID, err := attrs.String("channel")
+	if err != nil {
+		return err
+	}
+	m.channelID = channelID
+
+	threshold, err := attrs.Int64("threshold")
+	if err != nil {
+		return err
+	}
+	m.threshold = threshold
+
+	schedule, err := attrs.String("schedule")
+	if err != nil {
+		schedule = "0 18 * * *"
+	}
+	m.schedule = schedule
+
+	configuredDistricts.mu.Lock()
+	configuredDistricts.seen[m.district] = true
+	configuredDistricts.mu.Unlock()
+
+	return nil
+}
+
+// Setup stores the dependencies shared across all modules.
+func (m *covidIncidenceModule) Setup(deps ModuleDeps) error {
+	m.discord = deps.Discord
+	m.client = deps.Client
+	return nil
+}
+
+// Schedule returns the cron expression this module instance runs on.
+func (m *covidIncidenceModule) Schedule() string {
+	return m.schedule
+}
+
+// Execute fetches the current incidence for district and posts it to channelID, skipping the post
+// entirely if the upstream data hasn't changed since the previous tick.
+func (m *covidIncidenceModule) Execute(ctx context.Context) error {
+	metricFetchesTotal.Inc()
+
+	data, err := fetchData(ctx, m.client, m.district)
+	if err != nil {
+		metricFetchErrorsTotal.Inc()
+		return fmt.Errorf("could not fetch COVID data: %w", err)
+	}
+
+	markFetchSucceeded()
+
+	if data.Meta.LastUpdate == m.lastUpdate {
+		return nil
+	}
+	m.lastUpdate = data.Meta.LastUpdate
+
+	d, ok := data.Data[m.district]
+	if !ok {
+		return fmt.Errorf("unknown district %s", m.district)
+	}
+
+	now := time.Now()
+	if err := recordSnapshot(m.district, now, data); err != nil {
+		return err
+	}
+
+	timestamp := getCurrentTimestamp()
+	incidence := uint32(d.WeekIncidence)
+
+	if err := m.postIncidence(m.channelID, m.threshold, timestamp, incidence); err != nil {
+		return err
+	}
+
+	m.postToSubscribers(timestamp, incidence)
+
+	return nil
+}
+
+// postIncidence posts the incidence line for the current tick to channelID, then pings everyone
+// in that channel once incidence has crossed threshold.
+func (m *covidIncidenceModule) postIncidence(channelID string, threshold int64, timestamp string, incidence uint32) error {
+	if _, err := m.discord.ChannelMessageSend(channelID, fmt.Sprintf("Inzidenzwert für den %s: **%d**\n%s", timestamp, incidence, m.trendLine())); err != nil {
+		return err
+	}
+
+	if int64(incidence) < threshold {
+		return nil
+	}
+
+	mention, err := everyone(m.discord, channelID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := m.discord.ChannelMessageSend(channelID, fmt.Sprintf("%s Distanzunterricht, wooow", mention)); err != nil {
+		return err
+	}
+
+	metricAlertsSentTotal.Inc()
+	return nil
+}
+
+// postToSubscribers posts this tick's incidence to every channel subscribed to m.district via
+// /subscribe, other than this module instance's own channelID, which Execute has already posted
+// to; a subscription for that channel is skipped. A single subscribed channel
+// failing to post does not fail the whole tick, since the other subscribers are unrelated.
+func (m *covidIncidenceModule) postToSubscribers(timestamp string, incidence uint32) {
+	for _, sub := range subscriptionsForDistrict(m.district) {
+		if sub.ChannelID == m.channelID {
+			continue
+		}
+
+		if err := m.postIncidence(sub.ChannelID, sub.Threshold, timestamp, incidence); err != nil {
+			appLog.Error().Err(err).Str("module", covidModuleName).Str("channel", sub.ChannelID).Msg("could not post subscription alert")
+		}
+	}
+}
+
+// trendLine renders the 7- and 14-day deltas plus a sparkline for m.district, falling back to an
+// empty string when no snapshot is recorded within either window or the history can't be read.
+// With a shorter history, the deltas are taken from the oldest snapshot there is.
+func (m *covidIncidenceModule) trendLine() string {
+	delta7, err7 := incidenceDelta(m.district, 7)
+	delta14, err14 := incidenceDelta(m.district, 14)
+	if err7 != nil || err14 != nil {
+		return ""
+	}
+
+	series, err := incidenceSeries(m.district, time.Now().AddDate(0, 0, -14))
+	if err != nil {
+		return ""
+	}
+
+	return fmt.Sprintf("7d: %+.1f · 14d: %+.1f · %s", delta7, delta14, sparkline(series))
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jpillora/backoff"
+)
+
+// districtsRoute is the route template used to key per-route rate-limit buckets, since every
+// district shares the same endpoint shape but the API rate-limits per path.
+const districtsRoute = "/districts/{key}"
+
+// maxAttempts bounds how many attempts restClient makes for a single call, the first one included.
+const maxAttempts = 5
+
+// routeBucket tracks the rate-limit budget the API last reported for a single route template.
+type routeBucket struct {
+	remaining int
+	resetAt   time.Time
+}
+
+// restClient wraps an http.Client with per-route rate limiting, a shared global 429 backoff, and
+// retries with jitter for transient failures, so callers can poll api.corona-zahlen.org freely.
+type restClient struct {
+	client *http.Client
+
+	mu          sync.Mutex
+	buckets     map[string]*routeBucket
+	globalUntil time.Time
+}
+
+// newRESTClient returns a restClient wrapping client.
+func newRESTClient(client *http.Client) *restClient {
+	return &restClient{
+		client:  client,
+		buckets: map[string]*routeBucket{},
+	}
+}
+
+// getJSON performs a GET against url, keyed under route for rate limiting, and unmarshals the
+// response body into out. It blocks until the route's bucket has budget, retries transient
+// failures with exponential backoff, and honors ctx cancellation throughout.
+func (c *restClient) getJSON(ctx context.Context, route, url string, out interface{}) error {
+	b := &backoff.Backoff{
+		Min:    200 * time.Millisecond,
+		Max:    10 * time.Second,
+		Jitter: true,
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if err := c.awaitBudget(ctx, route); err != nil {
+			return err
+		}
+
+		bytes, status, headers, err := c.do(ctx, url)
+		if err != nil {
+			lastErr = err
+			if !sleepBackoff(ctx, b) {
+				return lastErr
+			}
+			continue
+		}
+
+		c.updateBucket(route, headers)
+
+		if status == http.StatusTooManyRequests {
+			c.applyGlobalBackoff(headers)
+			lastErr = fmt.Errorf("rate limited by upstream")
+			if !sleepBackoff(ctx, b) {
+				return lastErr
+			}
+			continue
+		}
+
+		if status >= 500 {
+			lastErr = fmt.Errorf("upstream returned %d", status)
+			if !sleepBackoff(ctx, b) {
+				return lastErr
+			}
+			continue
+		}
+
+		if status >= 400 {
+			return fmt.Errorf("upstream returned %d", status)
+		}
+
+		return json.Unmarshal(bytes, out)
+	}
+
+	return fmt.Errorf("giving up after %d attempts: %w", maxAttempts, lastErr)
+}
+
+// do performs the actual HTTP round-trip and reads the response body.
+func (c *restClient) do(ctx context.Context, url string) ([]byte, int, http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	bytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	return bytes, resp.StatusCode, resp.Header, nil
+}
+
+// awaitBudget blocks until route has rate-limit budget and any global 429 backoff has elapsed.
+func (c *restClient) awaitBudget(ctx context.Context, route string) error {
+	for {
+		c.mu.Lock()
+		wait := time.Duration(0)
+
+		if until := c.globalUntil; until.After(time.Now()) {
+			wait = time.Until(until)
+		} else if b, ok := c.buckets[route]; ok && b.remaining <= 0 && b.resetAt.After(time.Now()) {
+			wait = time.Until(b.resetAt)
+		}
+		c.mu.Unlock()
+
+		if wait <= 0 {
+			return nil
+		}
+
+		metricRateLimitWaitsTotal.Inc()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// updateBucket records the rate-limit budget the upstream reported for route.
+func (c *restClient) updateBucket(route string, headers http.Header) {
+	remaining, remErr := strconv.Atoi(headers.Get("X-RateLimit-Remaining"))
+	reset, resetErr := strconv.ParseInt(headers.Get("X-RateLimit-Reset"), 10, 64)
+	if remErr != nil || resetErr != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.buckets[route] = &routeBucket{
+		remaining: remaining,
+		resetAt:   time.Unix(reset, 0),
+	}
+}
+
+// applyGlobalBackoff sets a shared cooldown across all routes after a 429, honoring Retry-After
+// when present.
+func (c *restClient) applyGlobalBackoff(headers http.Header) {
+	wait := 30 * time.Second
+	if seconds, err := strconv.Atoi(headers.Get("Retry-After")); err == nil {
+		wait = time.Duration(seconds) * time.Second
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.globalUntil = time.Now().Add(wait)
+}
+
+// sleepBackoff sleeps for b's next backoff duration, returning false if ctx is cancelled first.
+func sleepBackoff(ctx context.Context, b *backoff.Backoff) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(b.Duration()):
+		return true
+	}
+}
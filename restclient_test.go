@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestUpdateBucket(t *testing.T) {
+	tests := []struct {
+		name          string
+		headers       http.Header
+		wantRemaining int
+		wantRecorded  bool
+	}{
+		{
+			name:          "valid headers record the bucket",
+			headers:       http.Header{"X-Ratelimit-Remaining": {"3"}, "X-Ratelimit-Reset": {"1700000000"}},
+			wantRemaining: 3,
+			wantRecorded:  true,
+		},
+		{
+			name:         "missing remaining header leaves the bucket untouched",
+			headers:      http.Header{"X-Ratelimit-Reset": {"1700000000"}},
+			wantRecorded: false,
+		},
+		{
+			name:         "non-numeric reset header leaves the bucket untouched",
+			headers:      http.Header{"X-Ratelimit-Remaining": {"3"}, "X-Ratelimit-Reset": {"not-a-number"}},
+			wantRecorded: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newRESTClient(nil)
+			c.updateBucket(districtsRoute, tt.headers)
+
+			b, ok := c.buckets[districtsRoute]
+			if ok != tt.wantRecorded {
+				t.Fatalf("bucket recorded = %v, want %v", ok, tt.wantRecorded)
+			}
+			if tt.wantRecorded && b.remaining != tt.wantRemaining {
+				t.Errorf("bucket.remaining = %d, want %d", b.remaining, tt.wantRemaining)
+			}
+		})
+	}
+}
+
+func TestApplyGlobalBackoff(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers http.Header
+		want    time.Duration
+	}{
+		{
+			name:    "defaults to 30 seconds without Retry-After",
+			headers: http.Header{},
+			want:    30 * time.Second,
+		},
+		{
+			name:    "honors Retry-After when present",
+			headers: http.Header{"Retry-After": {"5"}},
+			want:    5 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newRESTClient(nil)
+
+			before := time.Now()
+			c.applyGlobalBackoff(tt.headers)
+			after := time.Now()
+
+			wantMin := before.Add(tt.want)
+			wantMax := after.Add(tt.want)
+			if c.globalUntil.Before(wantMin) || c.globalUntil.After(wantMax) {
+				t.Errorf("globalUntil = %v, want between %v and %v", c.globalUntil, wantMin, wantMax)
+			}
+		})
+	}
+}
+
+func TestAwaitBudget(t *testing.T) {
+	t.Run("returns immediately once the bucket has budget", func(t *testing.T) {
+		c := newRESTClient(nil)
+		c.buckets[districtsRoute] = &routeBucket{remaining: 1, resetAt: time.Now().Add(time.Hour)}
+
+		if err := c.awaitBudget(context.Background(), districtsRoute); err != nil {
+			t.Fatalf("awaitBudget: %v", err)
+		}
+	})
+
+	t.Run("blocks until an exhausted bucket resets", func(t *testing.T) {
+		c := newRESTClient(nil)
+		resetAt := time.Now().Add(100 * time.Millisecond)
+		c.buckets[districtsRoute] = &routeBucket{remaining: 0, resetAt: resetAt}
+
+		start := time.Now()
+		if err := c.awaitBudget(context.Background(), districtsRoute); err != nil {
+			t.Fatalf("awaitBudget: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed < resetAt.Sub(start) {
+			t.Errorf("awaitBudget returned before the bucket reset at %v", resetAt)
+		}
+	})
+
+	t.Run("returns the context error once cancelled", func(t *testing.T) {
+		c := newRESTClient(nil)
+		c.globalUntil = time.Now().Add(time.Hour)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		if err := c.awaitBudget(ctx, districtsRoute); err != ctx.Err() {
+			t.Fatalf("awaitBudget() error = %v, want %v", err, ctx.Err())
+		}
+	})
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// subscriptionsPath is where per-channel subscriptions created via /subscribe are persisted.
+const subscriptionsPath = "subscriptions.json"
+
+// subscription is a single channel's district/threshold pair, as created via /subscribe.
+type subscription struct {
+	GuildID   string `json:"guildId"`
+	ChannelID string `json:"channelId"`
+	District  string `json:"district"`
+	Threshold int64  `json:"threshold"`
+}
+
+// subscriptionStore guards the in-memory subscriptions and their on-disk copy.
+var subscriptionStore = struct {
+	mu   sync.Mutex
+	subs []subscription
+}{}
+
+// addSubscription persists a subscription for channelID, replacing any existing one for the same
+// channel and district.
+func addSubscription(guildID, channelID, district string, threshold int64) error {
+	subscriptionStore.mu.Lock()
+	defer subscriptionStore.mu.Unlock()
+
+	for i, sub := range subscriptionStore.subs {
+		if sub.ChannelID == channelID && sub.District == district {
+			subscriptionStore.subs[i].Threshold = threshold
+			return saveSubscriptionsLocked()
+		}
+	}
+
+	subscriptionStore.subs = append(subscriptionStore.subs, subscription{
+		GuildID:   guildID,
+		ChannelID: channelID,
+		District:  district,
+		Threshold: threshold,
+	})
+
+	return saveSubscriptionsLocked()
+}
+
+// removeSubscription deletes the subscription for channelID and district, if any.
+func removeSubscription(channelID, district string) error {
+	subscriptionStore.mu.Lock()
+	defer subscriptionStore.mu.Unlock()
+
+	for i, sub := range subscriptionStore.subs {
+		if sub.ChannelID == channelID && sub.District == district {
+			subscriptionStore.subs = append(subscriptionStore.subs[:i], subscriptionStore.subs[i+1:]...)
+			return saveSubscriptionsLocked()
+		}
+	}
+
+	return fmt.Errorf("no subscription for district %s in this channel", district)
+}
+
+// listSubscriptions returns every subscription belonging to guildID.
+func listSubscriptions(guildID string) []subscription {
+	subscriptionStore.mu.Lock()
+	defer subscriptionStore.mu.Unlock()
+
+	var subs []subscription
+	for _, sub := range subscriptionStore.subs {
+		if sub.GuildID == guildID {
+			subs = append(subs, sub)
+		}
+	}
+
+	return subs
+}
+
+// subscriptionsForDistrict returns every subscription for district, across every guild, so a
+// module's scheduled tick can fan an alert out to every channel subscribed to its district.
+func subscriptionsForDistrict(district string) []subscription {
+	subscriptionStore.mu.Lock()
+	defer subscriptionStore.mu.Unlock()
+
+	var subs []subscription
+	for _, sub := range subscriptionStore.subs {
+		if sub.District == district {
+			subs = append(subs, sub)
+		}
+	}
+
+	return subs
+}
+
+// loadSubscriptions reads subscriptionsPath into memory, if it exists.
+func loadSubscriptions() error {
+	subscriptionStore.mu.Lock()
+	defer subscriptionStore.mu.Unlock()
+
+	bytes, err := os.ReadFile(subscriptionsPath)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("could not read subscriptions file: %w", err)
+	}
+
+	return json.Unmarshal(bytes, &subscriptionStore.subs)
+}
+
+// saveSubscriptionsLocked writes subscriptionStore.subs to subscriptionsPath. Callers must hold
+// subscriptionStore.mu.
+func saveSubscriptionsLocked() error {
+	bytes, err := json.MarshalIndent(subscriptionStore.subs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not marshal subscriptions: %w", err)
+	}
+
+	return os.WriteFile(subscriptionsPath, bytes, 0o644)
+}